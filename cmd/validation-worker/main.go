@@ -0,0 +1,75 @@
+// Command validation-worker runs a gRPC server wrapping a local
+// ArbitratorSpawner or JitSpawner, so that a validator.RemoteSpawner
+// elsewhere on the network can dispatch validation runs to it.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+
+	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/grpc"
+
+	"github.com/offchainlabs/nitro/validator"
+	"github.com/offchainlabs/nitro/validator/validationclient"
+)
+
+func main() {
+	listenAddr := flag.String("listen-addr", "localhost:0", "address to listen for gRPC validation requests on")
+	mode := flag.String("mode", "arbitrator", `which spawner to wrap: "arbitrator" or "jit"`)
+	machinesPath := flag.String("machines-path", "", "path to the root of the prebuilt WASM machines")
+	flag.Parse()
+
+	if err := mainImpl(*listenAddr, *mode, *machinesPath); err != nil {
+		log.Crit("validation-worker exited with error", "err", err)
+	}
+}
+
+func mainImpl(listenAddr, mode, machinesPath string) error {
+	locator, err := validator.NewMachineLocator(machinesPath)
+	if err != nil {
+		return fmt.Errorf("locating WASM machines: %w", err)
+	}
+
+	spawner, err := newWrappedSpawner(locator, mode)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	spawner.Start(ctx)
+	defer spawner.Stop()
+
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", listenAddr, err)
+	}
+	grpcServer := grpc.NewServer(validationclient.ServerOptions()...)
+	validationclient.RegisterValidationServerServer(grpcServer, validator.NewWorkerServer(spawner))
+	log.Info("validation worker listening", "addr", listener.Addr(), "mode", mode)
+	return grpcServer.Serve(listener)
+}
+
+func newWrappedSpawner(locator *validator.MachineLocator, mode string) (validator.ValidationSpawner, error) {
+	switch mode {
+	case "arbitrator":
+		config := validator.DefaultArbitratorSpawnerConfig
+		spawner, err := validator.NewArbitratorSpawner(locator, func() *validator.ArbitratorSpawnerConfig { return &config })
+		if err != nil {
+			return nil, fmt.Errorf("creating arbitrator spawner: %w", err)
+		}
+		return spawner, nil
+	case "jit":
+		config := validator.DefaultJitSpawnerConfig
+		fatalErrChan := make(chan error, 1)
+		spawner, err := validator.NewJitSpawner(locator, func() *validator.JitSpawnerConfig { return &config }, fatalErrChan)
+		if err != nil {
+			return nil, fmt.Errorf("creating jit spawner: %w", err)
+		}
+		return spawner, nil
+	default:
+		return nil, fmt.Errorf(`unknown -mode %q, want "arbitrator" or "jit"`, mode)
+	}
+}
@@ -0,0 +1,391 @@
+package validator
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/offchainlabs/nitro/util/stopwaiter"
+	"github.com/offchainlabs/nitro/validator/validationclient"
+	flag "github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// RemoteSpawnerConfig configures a RemoteSpawner, which implements
+// ValidationSpawner by shipping validation work to a pool of remote worker
+// processes over gRPC instead of executing it locally.
+type RemoteSpawnerConfig struct {
+	Endpoints                      []string `koanf:"endpoints"`
+	TLSCert                        string   `koanf:"tls-cert"`
+	PerEndpointConcurrentRunsLimit int      `koanf:"per-endpoint-concurrent-runs-limit"`
+}
+
+type RemoteSpawnerConfigFetcher func() *RemoteSpawnerConfig
+
+var DefaultRemoteSpawnerConfig = RemoteSpawnerConfig{
+	Endpoints:                      []string{},
+	TLSCert:                        "",
+	PerEndpointConcurrentRunsLimit: 0,
+}
+
+func RemoteSpawnerConfigAddOptions(prefix string, f *flag.FlagSet) {
+	f.StringSlice(prefix+".endpoints", DefaultRemoteSpawnerConfig.Endpoints, "remote validation worker gRPC endpoints (host:port) to load-balance Launch calls across")
+	f.String(prefix+".tls-cert", DefaultRemoteSpawnerConfig.TLSCert, "path to a PEM certificate used as the trusted root when verifying remote validation workers over TLS; empty disables TLS")
+	f.Int(prefix+".per-endpoint-concurrent-runs-limit", DefaultRemoteSpawnerConfig.PerEndpointConcurrentRunsLimit, "assumed concurrent run limit for an endpoint until its worker reports its own Room (0 = unlimited)")
+}
+
+// unlimitedRoom is the seeded room value for a worker whose
+// PerEndpointConcurrentRunsLimit is 0 ("unlimited"), so pickWorkers/Room
+// don't read that worker as having no capacity before its first Room poll
+// lands.
+const unlimitedRoom = math.MaxInt32
+
+// grpcMaxMessageBytes raises the client's message size limit above gRPC's
+// 4 MiB default as a second line of defense on top of chunking in
+// sendValidationInput, in case a single batch or preimage doesn't fit
+// within launchChunkTargetBytes on its own.
+const grpcMaxMessageBytes = 16 * 1024 * 1024
+
+// remoteWorker tracks one gRPC connection to a validation worker, along with
+// the last Room() value it reported. Before the first poll lands, room holds
+// PerEndpointConcurrentRunsLimit, or unlimitedRoom if that's 0.
+//
+// room is only refreshed once every roomPollInterval, which is too coarse on
+// its own to keep a burst of concurrent Launch calls from all landing on the
+// same "most free" worker: inFlight tracks runs this process has dispatched
+// to the worker but not yet heard back from, and is subtracted from room to
+// get available(), mirroring how the local spawners subtract their live
+// in-flight count from Room().
+type remoteWorker struct {
+	endpoint string
+	conn     *grpc.ClientConn
+	client   validationclient.ValidationServerClient
+
+	mu       sync.Mutex
+	room     int
+	inFlight int
+}
+
+// available returns the worker's free capacity as best known right now:
+// the last polled Room() value minus every run this process has dispatched
+// to the worker and not yet heard back from. inFlight is not reset when a
+// poll lands, so runs that were already in flight at poll time, and thus
+// already counted in the worker's reported Room(), are subtracted as well.
+func (w *remoteWorker) available() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.room - w.inFlight
+}
+
+func (w *remoteWorker) acquire() {
+	w.mu.Lock()
+	w.inFlight++
+	w.mu.Unlock()
+}
+
+func (w *remoteWorker) release() {
+	w.mu.Lock()
+	w.inFlight--
+	w.mu.Unlock()
+}
+
+// RemoteSpawner implements ValidationSpawner by load-balancing Launch calls
+// across a pool of remote workers, each of which wraps a local
+// ArbitratorSpawner or JitSpawner behind a validationclient.Server. Workers
+// are expected to advertise free capacity through Room(), mirroring the
+// local spawners' own Room() computation.
+type RemoteSpawner struct {
+	stopwaiter.StopWaiter
+	config  RemoteSpawnerConfigFetcher
+	name    string
+	workers []*remoteWorker
+}
+
+func NewRemoteSpawner(name string, config RemoteSpawnerConfigFetcher) (*RemoteSpawner, error) {
+	creds, err := dialCredentials(config().TLSCert)
+	if err != nil {
+		return nil, err
+	}
+	endpoints := config().Endpoints
+	if len(endpoints) == 0 {
+		return nil, errors.New("remote validation spawner configured with no endpoints")
+	}
+	workers := make([]*remoteWorker, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		conn, err := grpc.NewClient(
+			endpoint,
+			grpc.WithTransportCredentials(creds),
+			grpc.WithDefaultCallOptions(
+				grpc.MaxCallSendMsgSize(grpcMaxMessageBytes),
+				grpc.MaxCallRecvMsgSize(grpcMaxMessageBytes),
+			),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("dialing validation worker %s: %w", endpoint, err)
+		}
+		initialRoom := config().PerEndpointConcurrentRunsLimit
+		if initialRoom == 0 {
+			initialRoom = unlimitedRoom
+		}
+		workers = append(workers, &remoteWorker{
+			endpoint: endpoint,
+			conn:     conn,
+			client:   validationclient.NewValidationServerClient(conn),
+			room:     initialRoom,
+		})
+	}
+	return &RemoteSpawner{
+		config:  config,
+		name:    "remote(" + name + ")",
+		workers: workers,
+	}, nil
+}
+
+func dialCredentials(certPath string) (credentials.TransportCredentials, error) {
+	if certPath == "" {
+		return insecure.NewCredentials(), nil
+	}
+	pemCerts, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading tls-cert %s: %w", certPath, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemCerts) {
+		return nil, fmt.Errorf("no certificates found in %s", certPath)
+	}
+	return credentials.NewTLS(&tls.Config{RootCAs: pool}), nil
+}
+
+func (s *RemoteSpawner) Start(ctx_in context.Context) {
+	s.StopWaiter.Start(ctx_in, s)
+	for _, w := range s.workers {
+		w := w
+		s.LaunchThread(func(ctx context.Context) { s.pollRoom(ctx, w) })
+	}
+}
+
+// roomPollInterval is how often Room() is re-queried from each worker. It
+// only needs to be fresh enough for load-balancing, not real-time.
+const roomPollInterval = 2 * time.Second
+
+// pollRoom keeps w.room fresh so Launch can pick a worker without blocking
+// on an RPC for every call.
+func (s *RemoteSpawner) pollRoom(ctx context.Context, w *remoteWorker) {
+	update := func() {
+		resp, err := w.client.Room(ctx, &validationclient.RoomRequest{})
+		if err != nil {
+			log.Warn("failed to poll room from remote validation worker", "endpoint", w.endpoint, "err", err)
+			return
+		}
+		w.mu.Lock()
+		w.room = int(resp.Room)
+		w.mu.Unlock()
+	}
+	update()
+	ticker := time.NewTicker(roomPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			update()
+		}
+	}
+}
+
+func (s *RemoteSpawner) Name() string {
+	return s.name
+}
+
+func (s *RemoteSpawner) Room() int {
+	total := 0
+	for _, w := range s.workers {
+		total += w.available()
+	}
+	return total
+}
+
+func (s *RemoteSpawner) LatestWasmModuleRoot() (common.Hash, error) {
+	for _, w := range s.workers {
+		resp, err := w.client.LatestWasmModuleRoot(s.GetContext(), &validationclient.LatestWasmModuleRootRequest{})
+		if err != nil {
+			log.Warn("failed to query latest wasm module root from remote validation worker", "endpoint", w.endpoint, "err", err)
+			continue
+		}
+		return common.BytesToHash(resp.ModuleRoot), nil
+	}
+	return common.Hash{}, errors.New("no remote validation worker answered LatestWasmModuleRoot")
+}
+
+// pickWorkers returns the configured workers ordered by most free capacity
+// first (room minus in-flight runs this process has already dispatched), so
+// Launch tries the least-loaded worker and falls back to the next one on
+// failure. Capacity is re-read for each call, so a worker's position can
+// change between concurrent Launch calls as inFlight is acquired/released.
+func (s *RemoteSpawner) pickWorkers() []*remoteWorker {
+	ordered := append([]*remoteWorker(nil), s.workers...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].available() > ordered[j].available()
+	})
+	return ordered
+}
+
+func (s *RemoteSpawner) Launch(entry *ValidationInput, moduleRoot common.Hash) ValidationRun {
+	run := NewvalRun(moduleRoot)
+	s.LaunchThread(func(ctx context.Context) {
+		var lastErr error
+		for _, w := range s.pickWorkers() {
+			w.acquire()
+			result, err := s.launchOnWorker(ctx, w, entry, moduleRoot)
+			w.release()
+			if err == nil {
+				run.consumeResult(result, nil)
+				return
+			}
+			log.Warn("remote validation run failed, retrying on another worker", "endpoint", w.endpoint, "err", err)
+			lastErr = err
+		}
+		if lastErr == nil {
+			lastErr = errors.New("no remote validation workers available")
+		}
+		run.consumeResult(GoGlobalState{}, lastErr)
+	})
+	return run
+}
+
+// cancelRPCTimeout bounds the best-effort Cancel call made when the parent
+// context is cancelled; the original context is already done by then, so
+// this can't simply inherit it.
+const cancelRPCTimeout = 5 * time.Second
+
+func (s *RemoteSpawner) launchOnWorker(ctx context.Context, w *remoteWorker, entry *ValidationInput, moduleRoot common.Hash) (GoGlobalState, error) {
+	stream, err := w.client.Launch(ctx)
+	if err != nil {
+		return GoGlobalState{}, fmt.Errorf("opening launch stream to %s: %w", w.endpoint, err)
+	}
+	runID := fmt.Sprintf("%s-%d", moduleRoot.Hex(), entry.Id)
+
+	// On context cancellation, also send the worker a best-effort Cancel for
+	// this run. The worker then drops and closes the run; closing does not
+	// stop an execution already under way on the worker's spawner.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			cancelCtx, cancel := context.WithTimeout(context.Background(), cancelRPCTimeout)
+			defer cancel()
+			if _, err := w.client.Cancel(cancelCtx, &validationclient.CancelRequest{RunId: runID}); err != nil {
+				log.Warn("failed to cancel remote validation run", "endpoint", w.endpoint, "runId", runID, "err", err)
+			}
+		case <-done:
+		}
+	}()
+
+	if err := sendValidationInput(stream, runID, entry, moduleRoot); err != nil {
+		return GoGlobalState{}, fmt.Errorf("streaming validation input to %s: %w", w.endpoint, err)
+	}
+	var update *validationclient.LaunchUpdate
+	for {
+		update, err = stream.Recv()
+		if err != nil {
+			return GoGlobalState{}, fmt.Errorf("receiving result from %s: %w", w.endpoint, err)
+		}
+		if update.Ready {
+			break
+		}
+	}
+	if update.Error != "" {
+		return GoGlobalState{}, errors.New(update.Error)
+	}
+	if update.Result == nil {
+		return GoGlobalState{}, fmt.Errorf("ready update from %s had no result", w.endpoint)
+	}
+	return GoGlobalState{
+		BlockHash:  common.BytesToHash(update.Result.BlockHash),
+		SendRoot:   common.BytesToHash(update.Result.SendRoot),
+		Batch:      update.Result.Batch,
+		PosInBatch: update.Result.PosInBatch,
+	}, nil
+}
+
+// launchChunkTargetBytes bounds how much preimage/batch payload goes into a
+// single LaunchChunk, keeping messages safely under gRPC's default 4 MiB
+// max message size even for validation inputs with megabytes of preimages.
+const launchChunkTargetBytes = 3 * 1024 * 1024
+
+// sendValidationInput streams entry to the worker as a sequence of
+// LaunchChunks: the first carries the scalar fields and start state, and
+// batch/preimage payloads are spread across as many chunks as needed to
+// stay under launchChunkTargetBytes each. The last chunk sent has
+// Final = true.
+func sendValidationInput(stream validationclient.ValidationServer_LaunchClient, runID string, entry *ValidationInput, moduleRoot common.Hash) error {
+	pending := &validationclient.LaunchChunk{
+		RunId:         runID,
+		ModuleRoot:    moduleRoot.Bytes(),
+		Id:            entry.Id,
+		HasDelayedMsg: entry.HasDelayedMsg,
+		DelayedMsgNr:  entry.DelayedMsgNr,
+		DelayedMsg:    entry.DelayedMsg,
+		StartState: &validationclient.GlobalStateMessage{
+			BlockHash:  entry.StartState.BlockHash.Bytes(),
+			SendRoot:   entry.StartState.SendRoot.Bytes(),
+			Batch:      entry.StartState.Batch,
+			PosInBatch: entry.StartState.PosInBatch,
+		},
+	}
+	pendingBytes := len(entry.DelayedMsg)
+
+	flush := func(final bool) error {
+		pending.Final = final
+		if err := stream.Send(pending); err != nil {
+			return err
+		}
+		pending = &validationclient.LaunchChunk{RunId: runID}
+		pendingBytes = 0
+		return nil
+	}
+
+	for _, batch := range entry.BatchInfo {
+		if pendingBytes > 0 && pendingBytes+len(batch.Data) > launchChunkTargetBytes {
+			if err := flush(false); err != nil {
+				return err
+			}
+		}
+		pending.BatchInfo = append(pending.BatchInfo, &validationclient.BatchInfoMessage{Number: batch.Number, Data: batch.Data})
+		pendingBytes += len(batch.Data)
+	}
+	for hash, data := range entry.Preimages {
+		if pendingBytes > 0 && pendingBytes+len(data) > launchChunkTargetBytes {
+			if err := flush(false); err != nil {
+				return err
+			}
+		}
+		pending.Preimages = append(pending.Preimages, &validationclient.PreimageMessage{Hash: hash.Bytes(), Data: data})
+		pendingBytes += len(data)
+	}
+	return flush(true)
+}
+
+func (s *RemoteSpawner) Stop() {
+	s.StopOnly()
+	for _, w := range s.workers {
+		if err := w.conn.Close(); err != nil {
+			log.Warn("error closing connection to remote validation worker", "endpoint", w.endpoint, "err", err)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package validator
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	flag "github.com/spf13/pflag"
+	"golang.org/x/sys/unix"
+)
+
+// ValidationCacheConfig configures the optional on-disk cache of completed
+// validation results shared by ArbitratorSpawner and JitSpawner.
+type ValidationCacheConfig struct {
+	Enabled      bool   `koanf:"enabled"`
+	BaseDir      string `koanf:"base-dir"`
+	MaxSizeBytes int64  `koanf:"max-size-bytes"`
+}
+
+var DefaultValidationCacheConfig = ValidationCacheConfig{
+	Enabled:      false,
+	BaseDir:      "",
+	MaxSizeBytes: 2 * 1024 * 1024 * 1024,
+}
+
+func ValidationCacheConfigAddOptions(prefix string, f *flag.FlagSet) {
+	f.Bool(prefix+".enabled", DefaultValidationCacheConfig.Enabled, "persist validation results to disk so repeated runs of the same input after a crash or restart don't re-execute")
+	f.String(prefix+".base-dir", DefaultValidationCacheConfig.BaseDir, "directory to store cached validation results in; required if enabled")
+	f.Int64(prefix+".max-size-bytes", DefaultValidationCacheConfig.MaxSizeBytes, "approximate size budget for the on-disk validation cache; least-recently-used entries are evicted once it is exceeded")
+}
+
+var (
+	validationCacheHitCount      = metrics.NewRegisteredCounter("arb/validator/cache/hit", nil)
+	validationCacheMissCount     = metrics.NewRegisteredCounter("arb/validator/cache/miss", nil)
+	validationCacheErrorCount    = metrics.NewRegisteredCounter("arb/validator/cache/error", nil)
+	validationCacheEvictionCount = metrics.NewRegisteredCounter("arb/validator/cache/eviction", nil)
+)
+
+// validationCache is a process-shared, content-addressed cache of completed
+// validation runs, persisted as one file per entry under BaseDir. Entries
+// are keyed by the wasm module root being validated and a hash of the rest
+// of the ValidationInput, so a rerun of the same block against the same
+// machine after a restart can be served from disk instead of re-executing a
+// multi-second WASM machine.
+//
+// Multiple validator processes on the same host may point at the same
+// BaseDir: writes don't take the lock at all, since entries are written
+// atomically via temp-file-then-rename and are immutable once written; only
+// eviction scans take an exclusive flock on a lockfile under BaseDir, so
+// that two processes' scans never race with each other on os.Remove calls
+// or size accounting.
+type validationCache struct {
+	baseDir      string
+	maxSizeBytes int64
+
+	evictionMutex sync.Mutex
+}
+
+// newValidationCache returns nil if the cache is disabled.
+func newValidationCache(config ValidationCacheConfig) (*validationCache, error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+	if config.BaseDir == "" {
+		return nil, errors.New("validation cache enabled but base-dir is empty")
+	}
+	if err := os.MkdirAll(config.BaseDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating validation cache base-dir: %w", err)
+	}
+	return &validationCache{
+		baseDir:      config.BaseDir,
+		maxSizeBytes: config.MaxSizeBytes,
+	}, nil
+}
+
+func hashValidationInput(entry *ValidationInput) common.Hash {
+	h := sha256.New()
+	var buf8 [8]byte
+	writeUint64 := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf8[:], v)
+		h.Write(buf8[:])
+	}
+	writeUint64(entry.Id)
+	h.Write(entry.StartState.BlockHash.Bytes())
+	h.Write(entry.StartState.SendRoot.Bytes())
+	writeUint64(entry.StartState.Batch)
+	writeUint64(entry.StartState.PosInBatch)
+
+	batches := append([]BatchInfo(nil), entry.BatchInfo...)
+	sort.Slice(batches, func(i, j int) bool { return batches[i].Number < batches[j].Number })
+	for _, batch := range batches {
+		writeUint64(batch.Number)
+		h.Write(batch.Data)
+	}
+
+	if entry.HasDelayedMsg {
+		writeUint64(entry.DelayedMsgNr)
+		h.Write(entry.DelayedMsg)
+	}
+
+	preimageHashes := make([]common.Hash, 0, len(entry.Preimages))
+	for hash := range entry.Preimages {
+		preimageHashes = append(preimageHashes, hash)
+	}
+	sort.Slice(preimageHashes, func(i, j int) bool { return preimageHashes[i].Cmp(preimageHashes[j]) < 0 })
+	for _, hash := range preimageHashes {
+		h.Write(hash.Bytes())
+		h.Write(entry.Preimages[hash])
+	}
+
+	return common.BytesToHash(h.Sum(nil))
+}
+
+func (c *validationCache) entryPath(moduleRoot common.Hash, entry *ValidationInput) string {
+	return filepath.Join(c.baseDir, moduleRoot.Hex(), hashValidationInput(entry).Hex())
+}
+
+// Validate returns the cached result for entry under moduleRoot, if any.
+func (c *validationCache) Validate(moduleRoot common.Hash, entry *ValidationInput) (GoGlobalState, bool) {
+	path := c.entryPath(moduleRoot, entry)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		validationCacheMissCount.Inc(1)
+		return GoGlobalState{}, false
+	}
+	state, err := decodeGoGlobalState(data)
+	if err != nil {
+		log.Warn("validation cache entry unreadable, treating as a miss", "path", path, "err", err)
+		validationCacheErrorCount.Inc(1)
+		return GoGlobalState{}, false
+	}
+	validationCacheHitCount.Inc(1)
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return state, true
+}
+
+// Put persists result as the cached value for entry under moduleRoot, then
+// opportunistically evicts the least-recently-used entries if the cache has
+// grown past MaxSizeBytes.
+func (c *validationCache) Put(moduleRoot common.Hash, entry *ValidationInput, result GoGlobalState) error {
+	path := c.entryPath(moduleRoot, entry)
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating validation cache entry dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating validation cache temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(encodeGoGlobalState(result)); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("writing validation cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("closing validation cache entry: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("renaming validation cache entry into place: %w", err)
+	}
+	c.evictIfNeeded()
+	return nil
+}
+
+// Invalidate removes any cached result for entry under moduleRoot.
+func (c *validationCache) Invalidate(moduleRoot common.Hash, entry *ValidationInput) {
+	path := c.entryPath(moduleRoot, entry)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.Warn("failed to invalidate validation cache entry", "path", path, "err", err)
+	}
+}
+
+func (c *validationCache) evictIfNeeded() {
+	if c.maxSizeBytes <= 0 {
+		return
+	}
+	c.evictionMutex.Lock()
+	defer c.evictionMutex.Unlock()
+
+	unlock, err := c.flockExclusive()
+	if err != nil {
+		log.Warn("failed to lock validation cache for eviction scan", "err", err)
+		return
+	}
+	defer unlock()
+
+	type cacheFile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []cacheFile
+	var total int64
+	walkErr := filepath.Walk(c.baseDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(path) == ".lock" {
+			return nil
+		}
+		if strings.HasPrefix(filepath.Base(path), ".tmp-") {
+			// Write in progress via Put's temp-file-then-rename; not yet a
+			// real entry and must not be evicted out from under that Put.
+			return nil
+		}
+		files = append(files, cacheFile{path, info.Size(), info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if walkErr != nil {
+		log.Warn("failed to walk validation cache for eviction", "err", walkErr)
+		return
+	}
+	if total <= c.maxSizeBytes {
+		return
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		if total <= c.maxSizeBytes {
+			break
+		}
+		if err := os.Remove(f.path); err != nil {
+			continue
+		}
+		total -= f.size
+		validationCacheEvictionCount.Inc(1)
+	}
+}
+
+// flockExclusive takes an exclusive flock on a lockfile under BaseDir, so
+// that validator processes sharing this cache directory never run eviction
+// scans concurrently; a shared lock would let two scans race on the same
+// os.Remove calls and size accounting, which is exactly what this is meant
+// to prevent. Writes are atomic rename-based and don't need the lock.
+func (c *validationCache) flockExclusive() (func(), error) {
+	f, err := os.OpenFile(filepath.Join(c.baseDir, ".lock"), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := unix.Flock(int(f.Fd()), unix.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		_ = unix.Flock(int(f.Fd()), unix.LOCK_UN)
+		f.Close()
+	}, nil
+}
+
+const goGlobalStateEncodedLen = 32 + 32 + 8 + 8
+
+func encodeGoGlobalState(s GoGlobalState) []byte {
+	buf := make([]byte, 0, goGlobalStateEncodedLen)
+	buf = append(buf, s.BlockHash.Bytes()...)
+	buf = append(buf, s.SendRoot.Bytes()...)
+	var tmp [8]byte
+	binary.LittleEndian.PutUint64(tmp[:], s.Batch)
+	buf = append(buf, tmp[:]...)
+	binary.LittleEndian.PutUint64(tmp[:], s.PosInBatch)
+	buf = append(buf, tmp[:]...)
+	return buf
+}
+
+func decodeGoGlobalState(data []byte) (GoGlobalState, error) {
+	if len(data) != goGlobalStateEncodedLen {
+		return GoGlobalState{}, fmt.Errorf("invalid validation cache entry length %d", len(data))
+	}
+	return GoGlobalState{
+		BlockHash:  common.BytesToHash(data[0:32]),
+		SendRoot:   common.BytesToHash(data[32:64]),
+		Batch:      binary.LittleEndian.Uint64(data[64:72]),
+		PosInBatch: binary.LittleEndian.Uint64(data[72:80]),
+	}, nil
+}
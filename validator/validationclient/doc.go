@@ -0,0 +1,7 @@
+// Package validationclient holds the gRPC contract between a RemoteSpawner
+// (validator.RemoteSpawner) and the remote validation worker binaries it
+// talks to. The generated client/server code that server.go builds on is not
+// kept in this directory; it is produced from validation.proto via:
+//
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative validation.proto
+package validationclient
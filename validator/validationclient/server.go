@@ -0,0 +1,191 @@
+package validationclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/grpc"
+)
+
+// grpcMaxMessageBytes matches the client-side limit in
+// validator.grpcMaxMessageBytes; worker binaries should pass ServerOptions()
+// to grpc.NewServer so a LaunchChunk's payload is never rejected by gRPC's
+// 4 MiB default on either end of the connection.
+const grpcMaxMessageBytes = 16 * 1024 * 1024
+
+// ServerOptions returns the grpc.ServerOption set worker binaries should use
+// when constructing the grpc.Server that registers a Server.
+func ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(grpcMaxMessageBytes),
+		grpc.MaxSendMsgSize(grpcMaxMessageBytes),
+	}
+}
+
+// LocalSpawner is the subset of validator.ValidationSpawner that Server
+// needs, shaped so this package doesn't have to import the validator
+// package that already imports this one. A worker binary doesn't implement
+// LocalSpawner directly against an *validator.ArbitratorSpawner or
+// *validator.JitSpawner: it wraps one with validator.NewWorkerServer, which
+// adapts ValidationInput/ValidationRun to LocalValidationInput/
+// LocalValidationRun.
+type LocalSpawner interface {
+	Launch(entry *LocalValidationInput, moduleRoot common.Hash) LocalValidationRun
+	Room() int
+	LatestWasmModuleRoot() (common.Hash, error)
+}
+
+// LocalValidationInput mirrors validator.ValidationInput; Server assembles
+// one of these from the chunks of a Launch stream before handing it to the
+// wrapped spawner.
+type LocalValidationInput struct {
+	Id            uint64
+	HasDelayedMsg bool
+	DelayedMsgNr  uint64
+	DelayedMsg    []byte
+	Preimages     map[common.Hash][]byte
+	BatchInfo     []LocalBatchInfo
+	StartState    LocalGoGlobalState
+}
+
+type LocalBatchInfo struct {
+	Number uint64
+	Data   []byte
+}
+
+type LocalGoGlobalState struct {
+	BlockHash  common.Hash
+	SendRoot   common.Hash
+	Batch      uint64
+	PosInBatch uint64
+}
+
+// LocalValidationRun mirrors validator.ValidationRun.
+type LocalValidationRun interface {
+	WaitReady(ctx context.Context) error
+	Result() (LocalGoGlobalState, error)
+	Close()
+}
+
+// Server implements the generated ValidationServerServer interface, wrapping
+// a LocalSpawner so it can be driven by a RemoteSpawner over gRPC. Worker
+// binaries construct one via validator.NewWorkerServer, which adapts a real
+// *validator.ArbitratorSpawner or *validator.JitSpawner to LocalSpawner.
+type Server struct {
+	UnimplementedValidationServerServer
+
+	spawner LocalSpawner
+
+	mu   sync.Mutex
+	runs map[string]LocalValidationRun
+}
+
+func NewServer(spawner LocalSpawner) *Server {
+	return &Server{
+		spawner: spawner,
+		runs:    make(map[string]LocalValidationRun),
+	}
+}
+
+func (s *Server) Room(ctx context.Context, req *RoomRequest) (*RoomResponse, error) {
+	return &RoomResponse{Room: int32(s.spawner.Room())}, nil
+}
+
+func (s *Server) LatestWasmModuleRoot(ctx context.Context, req *LatestWasmModuleRootRequest) (*LatestWasmModuleRootResponse, error) {
+	root, err := s.spawner.LatestWasmModuleRoot()
+	if err != nil {
+		return nil, err
+	}
+	return &LatestWasmModuleRootResponse{ModuleRoot: root.Bytes()}, nil
+}
+
+func (s *Server) Cancel(ctx context.Context, req *CancelRequest) (*CancelResponse, error) {
+	s.mu.Lock()
+	run, ok := s.runs[req.RunId]
+	delete(s.runs, req.RunId)
+	s.mu.Unlock()
+	if ok {
+		run.Close()
+	}
+	return &CancelResponse{}, nil
+}
+
+func (s *Server) Launch(stream ValidationServer_LaunchServer) error {
+	var runID string
+	var moduleRoot common.Hash
+	input := &LocalValidationInput{Preimages: make(map[common.Hash][]byte)}
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return fmt.Errorf("launch stream closed before a final chunk was received for run %q", runID)
+		}
+		if err != nil {
+			return err
+		}
+		if runID == "" {
+			runID = chunk.RunId
+			moduleRoot = common.BytesToHash(chunk.ModuleRoot)
+			input.Id = chunk.Id
+			input.HasDelayedMsg = chunk.HasDelayedMsg
+			input.DelayedMsgNr = chunk.DelayedMsgNr
+			input.StartState = LocalGoGlobalState{
+				BlockHash:  common.BytesToHash(chunk.StartState.BlockHash),
+				SendRoot:   common.BytesToHash(chunk.StartState.SendRoot),
+				Batch:      chunk.StartState.Batch,
+				PosInBatch: chunk.StartState.PosInBatch,
+			}
+		}
+		if len(chunk.DelayedMsg) > 0 {
+			input.DelayedMsg = append(input.DelayedMsg, chunk.DelayedMsg...)
+		}
+		for _, batch := range chunk.BatchInfo {
+			input.BatchInfo = append(input.BatchInfo, LocalBatchInfo{Number: batch.Number, Data: batch.Data})
+		}
+		for _, preimage := range chunk.Preimages {
+			input.Preimages[common.BytesToHash(preimage.Hash)] = preimage.Data
+		}
+		if chunk.Final {
+			break
+		}
+	}
+
+	run := s.spawner.Launch(input, moduleRoot)
+	s.mu.Lock()
+	s.runs[runID] = run
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.runs, runID)
+		s.mu.Unlock()
+	}()
+
+	if err := run.WaitReady(stream.Context()); err != nil {
+		// WaitReady fails either because the run itself failed or because
+		// the stream's context is done (the client disconnected or
+		// cancelled). Either way the run is closed and the error is sent
+		// back. Close does not stop an execution already under way: the runs
+		// of validator.ArbitratorSpawner and validator.JitSpawner ignore it.
+		run.Close()
+		return stream.Send(&LaunchUpdate{RunId: runID, Ready: true, Error: err.Error()})
+	}
+	result, err := run.Result()
+	if err != nil {
+		log.Error("remote validation run failed", "runId", runID, "moduleRoot", moduleRoot, "err", err)
+		return stream.Send(&LaunchUpdate{RunId: runID, Ready: true, Error: err.Error()})
+	}
+	return stream.Send(&LaunchUpdate{
+		RunId: runID,
+		Ready: true,
+		Result: &GlobalStateMessage{
+			BlockHash:  result.BlockHash.Bytes(),
+			SendRoot:   result.SendRoot.Bytes(),
+			Batch:      result.Batch,
+			PosInBatch: result.PosInBatch,
+		},
+	})
+}
@@ -40,9 +40,10 @@ type ValidationRun interface {
 }
 
 type ArbitratorSpawnerConfig struct {
-	ConcurrentRuns     int    `koanf:"concurrent-runs-limit" reload:"hot"`
-	OutputPath         string `koanf:"output-path" reload:"hot"`
-	TargetMachineCount int    `koanf:"target-machine-count"`
+	ConcurrentRuns     int                   `koanf:"concurrent-runs-limit" reload:"hot"`
+	OutputPath         string                `koanf:"output-path" reload:"hot"`
+	TargetMachineCount int                   `koanf:"target-machine-count"`
+	Cache              ValidationCacheConfig `koanf:"cache"`
 }
 
 type ArbitratorSpawnerConfigFecher func() *ArbitratorSpawnerConfig
@@ -51,12 +52,14 @@ var DefaultArbitratorSpawnerConfig = ArbitratorSpawnerConfig{
 	ConcurrentRuns:     0,
 	OutputPath:         "./target/output",
 	TargetMachineCount: 4,
+	Cache:              DefaultValidationCacheConfig,
 }
 
 func ArbitratorSpawnerConfigAddOptions(prefix string, f *flag.FlagSet) {
 	f.Int(prefix+".concurrent-runs-limit", DefaultArbitratorSpawnerConfig.ConcurrentRuns, "number of cuncurrent runs")
 	f.String(prefix+".output-path", DefaultArbitratorSpawnerConfig.OutputPath, "path to write machines to")
 	f.Int(prefix+".target-machine-count", DefaultArbitratorSpawnerConfig.TargetMachineCount, "target machine count")
+	ValidationCacheConfigAddOptions(prefix+".cache", f)
 }
 
 func DefaultArbitratorSpawnerConfigFetcher() *ArbitratorSpawnerConfig {
@@ -64,8 +67,9 @@ func DefaultArbitratorSpawnerConfigFetcher() *ArbitratorSpawnerConfig {
 }
 
 type JitSpawnerConfig struct {
-	ConcurrentRuns int  `koanf:"concurrent-runs-limit" reload:"hot"`
-	Cranelift      bool `koanf:"cranelift"`
+	ConcurrentRuns int                   `koanf:"concurrent-runs-limit" reload:"hot"`
+	Cranelift      bool                  `koanf:"cranelift"`
+	Cache          ValidationCacheConfig `koanf:"cache"`
 }
 
 type JitSpawnerConfigFecher func() *JitSpawnerConfig
@@ -73,27 +77,32 @@ type JitSpawnerConfigFecher func() *JitSpawnerConfig
 var DefaultJitSpawnerConfig = JitSpawnerConfig{
 	ConcurrentRuns: 0,
 	Cranelift:      true,
+	Cache:          DefaultValidationCacheConfig,
 }
 
 func JitSpawnerConfigAddOptions(prefix string, f *flag.FlagSet) {
 	f.Int(prefix+".concurrent-runs-limit", DefaultJitSpawnerConfig.ConcurrentRuns, "number of cuncurrent runs")
 	f.Bool(prefix+".cranelift", DefaultJitSpawnerConfig.Cranelift, "use Cranelift instead of LLVM when validating blocks using the jit-accelerated block validator")
+	ValidationCacheConfigAddOptions(prefix+".cache", f)
 }
 
 // joint for comfort only - the two configs are entirely separate.
 type ValidationConfig struct {
 	Arbitrator ArbitratorSpawnerConfig `koanf:"arbitrator" reload:"hot"`
 	Jit        JitSpawnerConfig        `koanf:"jit" reload:"hot"`
+	Remote     RemoteSpawnerConfig     `koanf:"remote"`
 }
 
 var DefaultValidationConfig = ValidationConfig{
 	Jit:        DefaultJitSpawnerConfig,
 	Arbitrator: DefaultArbitratorSpawnerConfig,
+	Remote:     DefaultRemoteSpawnerConfig,
 }
 
 func ValidationConfigAddOptions(prefix string, f *flag.FlagSet) {
 	ArbitratorSpawnerConfigAddOptions(prefix+".arbitrator", f)
 	JitSpawnerConfigAddOptions(prefix+".jit", f)
+	RemoteSpawnerConfigAddOptions(prefix+".remote", f)
 }
 
 type ArbitratorSpawner struct {
@@ -102,6 +111,7 @@ type ArbitratorSpawner struct {
 	locator       *MachineLocator
 	machineLoader *ArbMachineLoader
 	config        ArbitratorSpawnerConfigFecher
+	cache         *validationCache
 }
 
 type readyMarker struct {
@@ -175,10 +185,15 @@ func (r *valRun) consumeResult(res GoGlobalState, err error) {
 
 func NewArbitratorSpawner(locator *MachineLocator, config ArbitratorSpawnerConfigFecher) (*ArbitratorSpawner, error) {
 	// TODO: preload machines
+	cache, err := newValidationCache(config().Cache)
+	if err != nil {
+		return nil, fmt.Errorf("configuring arbitrator validation cache: %w", err)
+	}
 	spawner := &ArbitratorSpawner{
 		locator:       locator,
 		machineLoader: NewArbMachineLoader(&DefaultArbitratorMachineConfig, locator),
 		config:        config,
+		cache:         cache,
 	}
 	return spawner, nil
 }
@@ -271,11 +286,24 @@ func (v *ArbitratorSpawner) execute(
 }
 
 func (v *ArbitratorSpawner) Launch(entry *ValidationInput, moduleRoot common.Hash) ValidationRun {
+	if v.cache != nil {
+		if state, ok := v.cache.Validate(moduleRoot, entry); ok {
+			run := NewvalRun(moduleRoot)
+			run.consumeResult(state, nil)
+			return run
+		}
+	}
 	atomic.AddInt32(&v.count, 1)
 	run := NewvalRun(moduleRoot)
 	v.LaunchThread(func(ctx context.Context) {
 		defer atomic.AddInt32(&v.count, -1)
-		run.consumeResult(v.execute(ctx, entry, moduleRoot))
+		res, err := v.execute(ctx, entry, moduleRoot)
+		if err == nil && v.cache != nil {
+			if cacheErr := v.cache.Put(moduleRoot, entry, res); cacheErr != nil {
+				log.Warn("failed to write validation cache entry", "err", cacheErr)
+			}
+		}
+		run.consumeResult(res, err)
 	})
 	return run
 }
@@ -417,6 +445,7 @@ type JitSpawner struct {
 	locator       *MachineLocator
 	machineLoader *JitMachineLoader
 	config        JitSpawnerConfigFecher
+	cache         *validationCache
 }
 
 func NewJitSpawner(locator *MachineLocator, config JitSpawnerConfigFecher, fatalErrChan chan error) (*JitSpawner, error) {
@@ -427,10 +456,15 @@ func NewJitSpawner(locator *MachineLocator, config JitSpawnerConfigFecher, fatal
 	if err != nil {
 		return nil, err
 	}
+	cache, err := newValidationCache(config().Cache)
+	if err != nil {
+		return nil, fmt.Errorf("configuring jit validation cache: %w", err)
+	}
 	spawner := &JitSpawner{
 		locator:       locator,
 		machineLoader: loader,
 		config:        config,
+		cache:         cache,
 	}
 	return spawner, nil
 }
@@ -468,11 +502,24 @@ func (s *JitSpawner) Name() string {
 }
 
 func (v *JitSpawner) Launch(entry *ValidationInput, moduleRoot common.Hash) ValidationRun {
+	if v.cache != nil {
+		if state, ok := v.cache.Validate(moduleRoot, entry); ok {
+			run := NewvalRun(moduleRoot)
+			run.consumeResult(state, nil)
+			return run
+		}
+	}
 	atomic.AddInt32(&v.count, 1)
 	run := NewvalRun(moduleRoot)
 	go func() {
 		defer atomic.AddInt32(&v.count, -1)
-		run.consumeResult(v.execute(v.GetContext(), entry, moduleRoot))
+		res, err := v.execute(v.GetContext(), entry, moduleRoot)
+		if err == nil && v.cache != nil {
+			if cacheErr := v.cache.Put(moduleRoot, entry, res); cacheErr != nil {
+				log.Warn("failed to write validation cache entry", "err", cacheErr)
+			}
+		}
+		run.consumeResult(res, err)
 	}()
 	return run
 }
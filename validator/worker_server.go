@@ -0,0 +1,92 @@
+package validator
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/validator/validationclient"
+)
+
+// NewWorkerServer wraps spawner (an *ArbitratorSpawner or *JitSpawner) as a
+// validationclient.Server, so a worker binary can expose it to RemoteSpawner
+// clients over gRPC.
+func NewWorkerServer(spawner ValidationSpawner) *validationclient.Server {
+	return validationclient.NewServer(&workerSpawnerAdapter{spawner: spawner})
+}
+
+// workerSpawnerAdapter adapts a ValidationSpawner to validationclient's
+// LocalSpawner, translating between ValidationInput/ValidationRun and their
+// Local* counterparts at the package boundary.
+type workerSpawnerAdapter struct {
+	spawner ValidationSpawner
+}
+
+func (a *workerSpawnerAdapter) Launch(entry *validationclient.LocalValidationInput, moduleRoot common.Hash) validationclient.LocalValidationRun {
+	run := a.spawner.Launch(fromLocalValidationInput(entry), moduleRoot)
+	return &workerRunAdapter{run: run}
+}
+
+func (a *workerSpawnerAdapter) Room() int {
+	return a.spawner.Room()
+}
+
+// wasmModuleRootSpawner is implemented by ArbitratorSpawner, but not by
+// JitSpawner: LatestWasmModuleRoot isn't part of the ValidationSpawner
+// interface, so it's probed for rather than required.
+type wasmModuleRootSpawner interface {
+	LatestWasmModuleRoot() (common.Hash, error)
+}
+
+func (a *workerSpawnerAdapter) LatestWasmModuleRoot() (common.Hash, error) {
+	s, ok := a.spawner.(wasmModuleRootSpawner)
+	if !ok {
+		return common.Hash{}, errors.New("wrapped spawner does not support LatestWasmModuleRoot")
+	}
+	return s.LatestWasmModuleRoot()
+}
+
+func fromLocalValidationInput(in *validationclient.LocalValidationInput) *ValidationInput {
+	batchInfo := make([]BatchInfo, len(in.BatchInfo))
+	for i, b := range in.BatchInfo {
+		batchInfo[i] = BatchInfo{Number: b.Number, Data: b.Data}
+	}
+	return &ValidationInput{
+		Id:            in.Id,
+		HasDelayedMsg: in.HasDelayedMsg,
+		DelayedMsgNr:  in.DelayedMsgNr,
+		DelayedMsg:    in.DelayedMsg,
+		Preimages:     in.Preimages,
+		BatchInfo:     batchInfo,
+		StartState: GoGlobalState{
+			BlockHash:  in.StartState.BlockHash,
+			SendRoot:   in.StartState.SendRoot,
+			Batch:      in.StartState.Batch,
+			PosInBatch: in.StartState.PosInBatch,
+		},
+	}
+}
+
+// workerRunAdapter adapts a ValidationRun to validationclient.LocalValidationRun.
+type workerRunAdapter struct {
+	run ValidationRun
+}
+
+func (a *workerRunAdapter) WaitReady(ctx context.Context) error {
+	return a.run.WaitReady(ctx)
+}
+
+func (a *workerRunAdapter) Result() (validationclient.LocalGoGlobalState, error) {
+	state, err := a.run.Result()
+	return validationclient.LocalGoGlobalState{
+		BlockHash:  state.BlockHash,
+		SendRoot:   state.SendRoot,
+		Batch:      state.Batch,
+		PosInBatch: state.PosInBatch,
+	}, err
+}
+
+func (a *workerRunAdapter) Close() {
+	a.run.Close()
+}